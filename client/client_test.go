@@ -136,6 +136,26 @@ func TestGetAPIPath(t *testing.T) {
 	}
 }
 
+func TestGetAPIPathContextOverride(t *testing.T) {
+	client := Client{
+		version:  "1.22",
+		basePath: "/",
+	}
+
+	ctx := ContextWithAPIVersion(context.TODO(), "1.41")
+	actual := client.getAPIPath(ctx, "/containers/json", nil)
+	assert.Check(t, is.Equal(actual, "/v1.41/containers/json"))
+
+	// the client's own version, and negotiation state, must be untouched by
+	// a per-request override.
+	assert.Check(t, is.Equal(client.version, "1.22"))
+	assert.Check(t, is.Equal(client.negotiated, false))
+
+	// without an override in the context, the client's version is used.
+	actual = client.getAPIPath(context.TODO(), "/containers/json", nil)
+	assert.Check(t, is.Equal(actual, "/v1.22/containers/json"))
+}
+
 func TestParseHostURL(t *testing.T) {
 	testcases := []struct {
 		host        string
@@ -162,6 +182,10 @@ func TestParseHostURL(t *testing.T) {
 			host:     "tcp://localhost:2476/path",
 			expected: &url.URL{Scheme: "tcp", Host: "localhost:2476", Path: "/path"},
 		},
+		{
+			host:     "ssh://user@host",
+			expected: &url.URL{Scheme: "ssh", Host: "host", User: url.User("user")},
+		},
 	}
 
 	for _, testcase := range testcases {
@@ -0,0 +1,30 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"net"
+)
+
+// ConnectionHelper enables connecting to a Docker daemon via a protocol that
+// net/http does not support natively, such as `ssh://`. Host is a dummy URL
+// that satisfies url.Parse, used as the base for HTTP requests made over the
+// connection produced by Dialer.
+type ConnectionHelper struct {
+	Dialer func(ctx context.Context, network, addr string) (net.Conn, error)
+	Host   string
+}
+
+// WithConnectionHelper configures the client to dial the daemon using the
+// provided ConnectionHelper instead of the transport's default dialer. This
+// is used to support non-HTTP DOCKER_HOST schemes such as `ssh://`.
+func WithConnectionHelper(helper *ConnectionHelper) Opt {
+	return func(c *Client) error {
+		if helper == nil {
+			return nil
+		}
+		if err := WithHost(helper.Host)(c); err != nil {
+			return err
+		}
+		return WithDialContext(helper.Dialer)(c)
+	}
+}
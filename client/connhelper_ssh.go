@@ -0,0 +1,129 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+
+	"github.com/pkg/errors"
+)
+
+// dialStdioCommand is the command the SSH connection helper runs on the
+// remote host to bridge the daemon's API socket over stdio. It is what the
+// `docker` CLI itself uses for `docker -H ssh://... info` and friends.
+var dialStdioCommand = []string{"docker", "system", "dial-stdio"}
+
+// NewSSHConnectionHelper returns a ConnectionHelper that connects to the
+// docker daemon on the remote host identified by daemonURL (an
+// `ssh://[user@]host[:port]` URL) by spawning `ssh` and running
+// `docker system dial-stdio` on the other end, using the resulting stdio
+// pipe as the connection.
+//
+// This mirrors what `docker -H ssh://...` does in the CLI, without requiring
+// callers to shell out to docker themselves.
+func NewSSHConnectionHelper(daemonURL string) (*ConnectionHelper, error) {
+	u, err := url.Parse(daemonURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse ssh URL")
+	}
+	if u.Scheme != "ssh" {
+		return nil, errors.Errorf("expected scheme ssh, got %s", u.Scheme)
+	}
+
+	args := sshArgs(u)
+	return &ConnectionHelper{
+		Host: "http://docker.example.invalid",
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return dialSSH(ctx, args)
+		},
+	}, nil
+}
+
+// sshArgs builds the `ssh` argument list (destination and options) to reach
+// the host and user encoded in u.
+func sshArgs(u *url.URL) []string {
+	var args []string
+	if u.User != nil && u.User.Username() != "" {
+		args = append(args, "-l", u.User.Username())
+	}
+	if port := u.Port(); port != "" {
+		args = append(args, "-p", port)
+	}
+	args = append(args, u.Hostname())
+	return args
+}
+
+// dialSSH spawns `ssh <args...> -- docker system dial-stdio` and returns a
+// net.Conn backed by the child process' stdin/stdout. The ssh process is
+// intentionally started with exec.Command rather than exec.CommandContext:
+// the resulting connection is meant to outlive the context of the dial call
+// that created it (it's returned to the caller as a regular net.Conn), so
+// its lifecycle is managed by Close, not by cancelling the dial context out
+// from under it. ctx is only consulted up front, to avoid spawning ssh at
+// all for a dial that's already been abandoned.
+//
+// The stdin/stdout pipes are built by hand with os.Pipe rather than
+// cmd.StdinPipe/cmd.StdoutPipe: those register their parent-side fd with
+// the Cmd so that Wait closes it once the process exits, and the os/exec
+// docs warn it's incorrect to rely on Wait before all reads from the pipe
+// have completed — exactly what stdioConn can't guarantee, since Read may
+// still be in flight on another goroutine for the connection's whole
+// lifetime. A Cmd only ever touches fds it opened itself, so owning these
+// pipes keeps Wait (below) from racing a concurrent Read the way
+// StdinPipe/StdoutPipe's would.
+func dialSSH(ctx context.Context, args []string) (net.Conn, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	args = append(append([]string{}, args...), "--")
+	args = append(args, dialStdioCommand...)
+
+	stdinR, stdinW, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		stdinR.Close()
+		stdinW.Close()
+		return nil, err
+	}
+
+	cmd := exec.Command("ssh", args...)
+	cmd.Stdin = stdinR
+	cmd.Stdout = stdoutW
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		stdinR.Close()
+		stdinW.Close()
+		stdoutR.Close()
+		stdoutW.Close()
+		return nil, errors.Wrap(err, "failed to start ssh")
+	}
+
+	// The child has its own duplicated copies of these fds now; close the
+	// ends it owns so stdoutR observes a real EOF once ssh exits instead of
+	// blocking forever on our own dangling write end.
+	stdinR.Close()
+	stdoutW.Close()
+
+	conn := &stdioConn{
+		cmd:    cmd,
+		in:     stdinW,
+		out:    stdoutR,
+		stderr: &stderr,
+		done:   make(chan struct{}),
+	}
+	go func() {
+		conn.waitErr = cmd.Wait()
+		close(conn.done)
+	}()
+
+	return conn, nil
+}
@@ -0,0 +1,49 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestWithConnectionHelperOverridesDialer(t *testing.T) {
+	var dialed bool
+	helper := &ConnectionHelper{
+		Host: "http://docker.example.invalid",
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			dialed = true
+			return nil, nil
+		},
+	}
+
+	cli, err := NewClientWithOpts(WithConnectionHelper(helper))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(cli.DaemonHost(), helper.Host))
+
+	tr := cli.client.Transport.(*http.Transport)
+	assert.Assert(t, tr.DialContext != nil)
+
+	_, _ = tr.DialContext(context.Background(), "tcp", "ignored")
+	assert.Check(t, dialed)
+}
+
+func TestNewSSHConnectionHelperParsesHostAndPort(t *testing.T) {
+	helper, err := NewSSHConnectionHelper("ssh://user@example.com:2222")
+	assert.NilError(t, err)
+	assert.Check(t, helper.Dialer != nil)
+	assert.Check(t, is.Equal(helper.Host, "http://docker.example.invalid"))
+
+	u, err := url.Parse("ssh://user@example.com:2222")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(sshArgs(u), []string{"-l", "user", "-p", "2222", "example.com"}))
+}
+
+func TestNewSSHConnectionHelperRejectsNonSSHScheme(t *testing.T) {
+	_, err := NewSSHConnectionHelper("tcp://example.com")
+	assert.Check(t, is.ErrorContains(err, "expected scheme ssh"))
+}
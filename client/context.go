@@ -0,0 +1,175 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/go-connections/tlsconfig"
+	"github.com/pkg/errors"
+)
+
+// dockerEndpoint is the name the docker CLI uses for the context endpoint
+// that describes how to reach the docker daemon.
+const dockerEndpoint = "docker"
+
+// contextMetadata mirrors the layout of the meta.json file the docker CLI
+// writes for each context under ~/.docker/contexts/meta/<context-id>/.
+type contextMetadata struct {
+	Name      string                  `json:"Name"`
+	Metadata  interface{}             `json:"Metadata,omitempty"`
+	Endpoints map[string]endpointMeta `json:"Endpoints"`
+}
+
+// endpointMeta is the subset of the docker CLI's docker-endpoint metadata
+// that the client needs in order to connect.
+//
+// The docker CLI's "docker" endpoint metadata has no field for arbitrary
+// HTTP headers — unlike DOCKER_CERT_PATH/DOCKER_TLS_VERIFY, headers aren't
+// something `docker context create` lets a user attach to an endpoint, so
+// there is nothing here for FromContext to read. FromContext/FromCurrentContext
+// therefore leave Client.customHTTPHeaders untouched; set them explicitly
+// with WithHTTPHeaders if needed.
+type endpointMeta struct {
+	Host          string `json:"Host,omitempty"`
+	SkipTLSVerify bool   `json:"SkipTLSVerify,omitempty"`
+}
+
+// configFile is the subset of the docker CLI's ~/.docker/config.json that
+// the client needs in order to resolve the current context.
+type configFile struct {
+	CurrentContext string `json:"currentContext,omitempty"`
+}
+
+// FromCurrentContext is an Opt that looks up the CLI context currently
+// selected via `docker context use` (or the DOCKER_CONTEXT environment
+// variable, which takes precedence) and configures the client to talk to
+// that context's docker endpoint. It is a no-op if neither is set.
+func FromCurrentContext() Opt {
+	return func(c *Client) error {
+		name, err := currentContextName()
+		if err != nil || name == "" {
+			return err
+		}
+		return FromContext(name)(c)
+	}
+}
+
+// FromContext is an Opt that configures the client's host and TLS material
+// from the docker CLI context with the given name, as stored under
+// ~/.docker/contexts. This allows library consumers to pick up whichever
+// endpoint a user has selected with `docker context use` without shelling
+// out to the CLI. See endpointMeta for why this does not also set HTTP
+// headers.
+func FromContext(name string) Opt {
+	return func(c *Client) error {
+		if name == "" {
+			return errors.New("context name cannot be empty")
+		}
+
+		meta, err := readContextMetadata(name)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read docker context %q", name)
+		}
+
+		ep, ok := meta.Endpoints[dockerEndpoint]
+		if !ok || ep.Host == "" {
+			return errors.Errorf("docker context %q has no docker endpoint configured", name)
+		}
+
+		if err := WithHost(ep.Host)(c); err != nil {
+			return err
+		}
+
+		tlsDir := filepath.Join(contextTLSDir(), contextDirName(name), dockerEndpoint)
+		if _, err := os.Stat(tlsDir); err == nil {
+			opts := tlsconfig.Options{
+				CAFile:             filepath.Join(tlsDir, "ca.pem"),
+				CertFile:           filepath.Join(tlsDir, "cert.pem"),
+				KeyFile:            filepath.Join(tlsDir, "key.pem"),
+				InsecureSkipVerify: ep.SkipTLSVerify,
+			}
+			tlsc, err := tlsconfig.Client(opts)
+			if err != nil {
+				return errors.Wrapf(err, "failed to load TLS material for docker context %q", name)
+			}
+			if err := WithHTTPClient(&http.Client{
+				Transport:     &http.Transport{TLSClientConfig: tlsc},
+				CheckRedirect: CheckRedirect,
+			})(c); err != nil {
+				return err
+			}
+			// re-apply the host now that the transport has been replaced, so
+			// that sockets.ConfigureTransport runs against the TLS-enabled
+			// transport rather than the default one.
+			if err := WithHost(ep.Host)(c); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func currentContextName() (string, error) {
+	if ctx := os.Getenv("DOCKER_CONTEXT"); ctx != "" {
+		return ctx, nil
+	}
+
+	path := filepath.Join(dockerConfigDir(), "config.json")
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var cfg configFile
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return "", errors.Wrapf(err, "failed to parse %s", path)
+	}
+	return cfg.CurrentContext, nil
+}
+
+func readContextMetadata(name string) (*contextMetadata, error) {
+	path := filepath.Join(contextMetaDir(), contextDirName(name), "meta.json")
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta contextMetadata
+	if err := json.Unmarshal(b, &meta); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", path)
+	}
+	return &meta, nil
+}
+
+// contextDirName returns the directory name the docker CLI stores a
+// context's metadata and TLS material under: the hex-encoded sha256 digest
+// of the context name.
+func contextDirName(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:])
+}
+
+func dockerConfigDir() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".docker")
+}
+
+func contextMetaDir() string {
+	return filepath.Join(dockerConfigDir(), "contexts", "meta")
+}
+
+func contextTLSDir() string {
+	return filepath.Join(dockerConfigDir(), "contexts", "tls")
+}
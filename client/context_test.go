@@ -0,0 +1,108 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/env"
+	"gotest.tools/v3/skip"
+)
+
+func writeContext(t *testing.T, configDir, name, meta string, tls bool) {
+	t.Helper()
+
+	dir := contextDirName(name)
+	metaDir := filepath.Join(configDir, "contexts", "meta", dir)
+	assert.NilError(t, os.MkdirAll(metaDir, 0o755))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(metaDir, "meta.json"), []byte(meta), 0o644))
+
+	if tls {
+		tlsDir := filepath.Join(configDir, "contexts", "tls", dir, dockerEndpoint)
+		assert.NilError(t, os.MkdirAll(tlsDir, 0o755))
+		for _, f := range []string{"ca.pem", "cert.pem", "key.pem"} {
+			src, err := ioutil.ReadFile(filepath.Join("testdata", f))
+			assert.NilError(t, err)
+			assert.NilError(t, ioutil.WriteFile(filepath.Join(tlsDir, f), src, 0o644))
+		}
+	}
+}
+
+func TestFromContextMissing(t *testing.T) {
+	configDir, err := ioutil.TempDir("", "docker-context-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(configDir)
+
+	defer env.PatchAll(t, map[string]string{"DOCKER_CONFIG": configDir, "DOCKER_CONTEXT": ""})()
+
+	_, err = NewClientWithOpts(FromContext("does-not-exist"))
+	assert.Check(t, is.ErrorContains(err, "failed to read docker context"))
+}
+
+func TestFromContextTLSEndpoint(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows")
+
+	configDir, err := ioutil.TempDir("", "docker-context-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(configDir)
+
+	const meta = `{"Name":"tls-ctx","Endpoints":{"docker":{"Host":"tcp://1.2.3.4:2376","SkipTLSVerify":false}}}`
+	writeContext(t, configDir, "tls-ctx", meta, true)
+
+	defer env.PatchAll(t, map[string]string{"DOCKER_CONFIG": configDir, "DOCKER_CONTEXT": ""})()
+
+	cli, err := NewClientWithOpts(FromContext("tls-ctx"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(cli.DaemonHost(), "tcp://1.2.3.4:2376"))
+
+	tr := cli.client.Transport.(*http.Transport)
+	assert.Assert(t, tr.TLSClientConfig != nil)
+}
+
+func TestFromCurrentContextPrecedence(t *testing.T) {
+	configDir, err := ioutil.TempDir("", "docker-context-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(configDir)
+
+	const meta = `{"Name":"from-env","Endpoints":{"docker":{"Host":"tcp://5.6.7.8:2376"}}}`
+	writeContext(t, configDir, "from-current", meta, false)
+
+	const cfg = `{"currentContext":"ignored-by-env-override"}`
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(configDir, "config.json"), []byte(cfg), 0o644))
+
+	// DOCKER_CONTEXT takes precedence over currentContext in config.json.
+	defer env.PatchAll(t, map[string]string{"DOCKER_CONFIG": configDir, "DOCKER_CONTEXT": "from-current"})()
+
+	cli, err := NewClientWithOpts(FromCurrentContext())
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(cli.DaemonHost(), "tcp://5.6.7.8:2376"))
+}
+
+func TestFromEnvAndFromContextPrecedence(t *testing.T) {
+	configDir, err := ioutil.TempDir("", "docker-context-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(configDir)
+
+	const meta = `{"Name":"ctx","Endpoints":{"docker":{"Host":"tcp://9.9.9.9:2376"}}}`
+	writeContext(t, configDir, "ctx", meta, false)
+
+	defer env.PatchAll(t, map[string]string{
+		"DOCKER_CONFIG": configDir,
+		"DOCKER_HOST":   "tcp://from-env:2376",
+	})()
+
+	// When both FromEnv and FromContext are supplied, the later Opt wins,
+	// consistent with how all client Opts are applied in order.
+	cli, err := NewClientWithOpts(FromEnv, FromContext("ctx"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(cli.DaemonHost(), "tcp://9.9.9.9:2376"))
+
+	cli, err = NewClientWithOpts(FromContext("ctx"), FromEnv)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(cli.DaemonHost(), "tcp://from-env:2376"))
+}
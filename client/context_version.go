@@ -0,0 +1,28 @@
+package client // import "github.com/docker/docker/client"
+
+import "context"
+
+// apiVersionKey is the context key under which ContextWithAPIVersion stores
+// a per-request API version override.
+type apiVersionKey struct{}
+
+// ContextWithAPIVersion returns a copy of ctx that, when passed to a Client
+// method, makes that single request use the given API version instead of
+// the client's negotiated or configured version. This lets a caller that
+// needs to talk to multiple daemon versions, or pin a specific endpoint to
+// a legacy version, do so without constructing multiple clients.
+//
+// It does not affect the client's own version or any negotiation state;
+// NegotiateAPIVersionPing and client.version are left untouched.
+func ContextWithAPIVersion(ctx context.Context, version string) context.Context {
+	return context.WithValue(ctx, apiVersionKey{}, version)
+}
+
+// versionFromContext returns the per-request API version override stored by
+// ContextWithAPIVersion, or "" if ctx carries none.
+func versionFromContext(ctx context.Context) string {
+	if v, ok := ctx.Value(apiVersionKey{}).(string); ok {
+		return v
+	}
+	return ""
+}
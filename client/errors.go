@@ -0,0 +1,14 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// checkResponseErr turns a non-2xx HTTP response into a Go error.
+func checkResponseErr(resp *http.Response) error {
+	if resp == nil || resp.StatusCode/100 == 2 {
+		return nil
+	}
+	return fmt.Errorf("Error response from daemon: status code %d", resp.StatusCode)
+}
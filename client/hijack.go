@@ -0,0 +1,137 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httputil"
+
+	"github.com/pkg/errors"
+)
+
+// Dialer returns a dial function that establishes a raw connection to the
+// daemon using the same host, TLS material and custom dialer (configured via
+// WithDialContext or WithConnectionHelper) that the client's transport would
+// use for a regular request. This lets tools that need their own framing
+// over the wire, such as the ssh connection helper's
+// `docker system dial-stdio`, reuse the client's fully-configured transport
+// instead of reimplementing dial logic.
+func (cli *Client) Dialer() func(context.Context) (net.Conn, error) {
+	return func(ctx context.Context) (net.Conn, error) {
+		if transport, ok := underlyingTransport(cli.client.Transport); ok {
+			// A custom dialer (WithDialContext, or WithHost/WithConnectionHelper
+			// configuring one under the hood) takes priority, but only when
+			// there's no TLS config to apply: WithHost's sockets.ConfigureTransport
+			// sets DialContext for every scheme, including plain tcp://, so a
+			// TLS endpoint would otherwise never get its handshake performed.
+			if transport.DialContext != nil && transport.TLSClientConfig == nil {
+				return transport.DialContext(ctx, cli.proto, cli.addr)
+			}
+			if transport.TLSClientConfig != nil {
+				return tlsDial(ctx, "tcp", cli.addr, transport.TLSClientConfig)
+			}
+		}
+
+		var d net.Dialer
+		switch cli.proto {
+		case "unix", "npipe":
+			return d.DialContext(ctx, cli.proto, cli.addr)
+		default:
+			return d.DialContext(ctx, "tcp", cli.addr)
+		}
+	}
+}
+
+func tlsDial(ctx context.Context, network, addr string, tlsConfig *tls.Config) (net.Conn, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := tlsConfig
+	if cfg.ServerName == "" {
+		// tls.Client skips hostname verification entirely when ServerName is
+		// empty, which would silently downgrade security relative to the
+		// normal http.Transport path (which always verifies the host). Clone
+		// rather than mutate, since tlsConfig is shared with the transport.
+		cfg = cfg.Clone()
+		cfg.ServerName = addr
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			cfg.ServerName = host
+		}
+	}
+
+	tlsConn := tls.Client(conn, cfg)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
+
+// DialHijack dials the daemon using Dialer and issues an HTTP request asking
+// the server to hijack and upgrade the connection to proto (e.g. "tcp" for
+// container attach/exec streams), returning the raw, upgraded net.Conn for
+// the caller to read and write on directly.
+//
+// path is the (unversioned) API request path; it is run through
+// cli.getAPIPath so it picks up the negotiated API version prefix and any
+// per-request version override set via ContextWithAPIVersion, exactly like
+// a regular request would. meta is merged into the request headers after
+// the client's own custom headers, so callers can add or override
+// individual headers for this one request.
+func (cli *Client) DialHijack(ctx context.Context, path, proto string, meta map[string][]string) (net.Conn, error) {
+	req, err := http.NewRequest(http.MethodPost, cli.getAPIPath(ctx, path, nil), nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Host = cli.addr
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", proto)
+
+	for k, v := range cli.customHTTPHeaders {
+		req.Header.Set(k, v)
+	}
+	for k, v := range meta {
+		req.Header[k] = v
+	}
+
+	conn, err := cli.Dialer()(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot connect to the Docker daemon")
+	}
+
+	clientconn := httputil.NewClientConn(conn, nil)
+	defer clientconn.Close()
+
+	resp, err := clientconn.Do(req)
+	if err != httputil.ErrPersistEOF && err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		resp.Body.Close()
+		return nil, errors.Errorf("unable to upgrade connection: received status %d from daemon", resp.StatusCode)
+	}
+
+	rwc, br := clientconn.Hijack()
+	return &hijackedConn{rwc, br}, nil
+}
+
+// hijackedConn wraps the net.Conn returned by a hijacked HTTP connection so
+// that bytes already buffered by the httputil.ClientConn (br) ahead of the
+// upgrade are not lost.
+type hijackedConn struct {
+	net.Conn
+	br *bufio.Reader
+}
+
+func (c *hijackedConn) Read(b []byte) (int, error) {
+	if c.br.Buffered() > 0 {
+		return c.br.Read(b)
+	}
+	return c.Conn.Read(b)
+}
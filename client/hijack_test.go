@@ -0,0 +1,152 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// startHijackServer starts a bare TCP server that reads a single HTTP
+// request, hands it to capture, then upgrades the connection and writes
+// magic so the test can assert bytes flow through the hijacked net.Conn.
+func startHijackServer(t *testing.T, capture func(*http.Request)) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		br := bufio.NewReader(conn)
+		req, err := http.ReadRequest(br)
+		if err != nil {
+			return
+		}
+		capture(req)
+
+		io.WriteString(conn, "HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n")
+		io.WriteString(conn, "magic")
+	}()
+
+	return ln.Addr().String()
+}
+
+// startHijackTLSServer is identical to startHijackServer except the listener
+// requires a TLS handshake before the HTTP request is read, so that a
+// client that skips the handshake (a plaintext dial) fails or hangs instead
+// of silently talking plaintext to it.
+func startHijackTLSServer(t *testing.T, capture func(*http.Request)) string {
+	t.Helper()
+	cert, err := tls.LoadX509KeyPair("testdata/cert.pem", "testdata/key.pem")
+	assert.NilError(t, err)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	assert.NilError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		br := bufio.NewReader(conn)
+		req, err := http.ReadRequest(br)
+		if err != nil {
+			return
+		}
+		capture(req)
+
+		io.WriteString(conn, "HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n")
+		io.WriteString(conn, "magic")
+	}()
+
+	return ln.Addr().String()
+}
+
+func newHijackTestClient(t *testing.T, addr string, headers map[string]string) *Client {
+	t.Helper()
+	cli, err := NewClientWithOpts(WithHost("tcp://"+addr), WithHTTPHeaders(headers))
+	assert.NilError(t, err)
+	return cli
+}
+
+func TestDialHijackIncludesCustomHeadersAndMeta(t *testing.T) {
+	var captured *http.Request
+	addr := startHijackServer(t, func(r *http.Request) { captured = r })
+
+	cli := newHijackTestClient(t, addr, map[string]string{"X-Custom": "client-header"})
+
+	conn, err := cli.DialHijack(context.Background(), "/containers/123/attach", "tcp", map[string][]string{
+		"X-Meta": {"meta-value"},
+	})
+	assert.NilError(t, err)
+	defer conn.Close()
+
+	buf := make([]byte, len("magic"))
+	_, err = io.ReadFull(conn, buf)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(buf), "magic"))
+
+	assert.Assert(t, captured != nil)
+	assert.Check(t, is.Equal(captured.Header.Get("X-Custom"), "client-header"))
+	assert.Check(t, is.Equal(captured.Header.Get("X-Meta"), "meta-value"))
+	assert.Check(t, is.Equal(captured.Header.Get("Upgrade"), "tcp"))
+}
+
+func TestDialHijackIncludesNegotiatedAPIVersion(t *testing.T) {
+	var captured *http.Request
+	addr := startHijackServer(t, func(r *http.Request) { captured = r })
+
+	cli, err := NewClientWithOpts(WithHost("tcp://" + addr))
+	assert.NilError(t, err)
+	cli.NegotiateAPIVersionPing(types.Ping{APIVersion: "1.30"})
+
+	conn, err := cli.DialHijack(context.Background(), "/containers/123/attach", "tcp", nil)
+	assert.NilError(t, err)
+	defer conn.Close()
+
+	assert.Assert(t, captured != nil)
+	assert.Check(t, is.Equal(captured.URL.Path, "/v1.30/containers/123/attach"))
+}
+
+// TestDialHijackPerformsTLSHandshake guards against the TLS path being
+// shadowed by a non-nil transport.DialContext: WithHost's
+// sockets.ConfigureTransport sets DialContext for every scheme, including
+// tcp://, so Dialer must still prefer TLSClientConfig when one is set.
+//
+// testdata/cert.pem carries a 127.0.0.1 SAN matching startHijackTLSServer's
+// listener address, and neither side sets InsecureSkipVerify, so this also
+// exercises tlsDial actually verifying the daemon's hostname rather than
+// passing only because verification was skipped.
+func TestDialHijackPerformsTLSHandshake(t *testing.T) {
+	var captured *http.Request
+	addr := startHijackTLSServer(t, func(r *http.Request) { captured = r })
+
+	cli, err := NewClientWithOpts(WithHost("tcp://"+addr), WithTLSClientConfig("testdata/ca.pem", "testdata/cert.pem", "testdata/key.pem"))
+	assert.NilError(t, err)
+
+	conn, err := cli.DialHijack(context.Background(), "/containers/123/attach", "tcp", nil)
+	assert.NilError(t, err)
+	defer conn.Close()
+
+	buf := make([]byte, len("magic"))
+	_, err = io.ReadFull(conn, buf)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(buf), "magic"))
+	assert.Assert(t, captured != nil)
+}
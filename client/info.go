@@ -0,0 +1,33 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/docker/docker/api/types"
+)
+
+// Info returns information about the docker server.
+func (cli *Client) Info(ctx context.Context) (types.Info, error) {
+	var info types.Info
+
+	cli.negotiateAPIVersionAutomatic(ctx)
+
+	req, err := http.NewRequest(http.MethodGet, cli.getAPIPath(ctx, "/info", nil), nil)
+	if err != nil {
+		return info, err
+	}
+	req = req.WithContext(ctx)
+	resp, err := cli.client.Do(req)
+	if err != nil {
+		return info, err
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponseErr(resp); err != nil {
+		return info, err
+	}
+	err = json.NewDecoder(resp.Body).Decode(&info)
+	return info, err
+}
@@ -0,0 +1,53 @@
+package client // import "github.com/docker/docker/client"
+
+import "net/http"
+
+// WithRequestMiddleware wraps the client's underlying http.RoundTripper with
+// mw, letting callers layer in cross-cutting concerns such as tracing,
+// metrics, auth token refresh, or custom per-request headers.
+//
+// Middlewares registered this way wrap whatever transport is configured at
+// the time the option runs, so WithRequestMiddleware should be passed to
+// NewClientWithOpts after options that configure the raw *http.Transport
+// (WithHost, WithTLSClientConfig, WithDialContext, WithConnectionHelper, ...);
+// once wrapped, the transport is no longer a *http.Transport, so those
+// options can no longer reach it directly.
+//
+// Client.Close and Client.Dialer still need to reach the underlying
+// *http.Transport, so mw's result should implement
+//
+//	Unwrap() http.RoundTripper
+//
+// returning the http.RoundTripper it wraps, the same convention used
+// elsewhere for error and context chains. RetryMiddleware does this; a
+// middleware that doesn't will cause Close to no longer close idle
+// connections and Dialer to fall back to a bare net.Dialer.
+func WithRequestMiddleware(mw func(http.RoundTripper) http.RoundTripper) Opt {
+	return func(c *Client) error {
+		c.client.Transport = mw(c.client.Transport)
+		return nil
+	}
+}
+
+// roundTripperUnwrapper is implemented by http.RoundTripper wrappers that
+// can hand back the http.RoundTripper they wrap, so that Close and Dialer
+// can see through a WithRequestMiddleware chain to the underlying
+// *http.Transport.
+type roundTripperUnwrapper interface {
+	Unwrap() http.RoundTripper
+}
+
+// underlyingTransport walks rt's Unwrap chain, if any, and returns the
+// *http.Transport at the bottom of it.
+func underlyingTransport(rt http.RoundTripper) (*http.Transport, bool) {
+	for {
+		if t, ok := rt.(*http.Transport); ok {
+			return t, true
+		}
+		u, ok := rt.(roundTripperUnwrapper)
+		if !ok {
+			return nil, false
+		}
+		rt = u.Unwrap()
+	}
+}
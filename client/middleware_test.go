@@ -0,0 +1,22 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+// TestRequestMiddlewareDoesNotBreakCloseOrDialer guards against
+// WithRequestMiddleware wrapping the transport in a way that Close and
+// Dialer can no longer see through: RetryMiddleware must implement Unwrap
+// so underlyingTransport can still find the *http.Transport underneath.
+func TestRequestMiddlewareDoesNotBreakCloseOrDialer(t *testing.T) {
+	cli, err := NewClientWithOpts(WithRequestMiddleware(RetryMiddleware(RetryPolicy{})))
+	assert.NilError(t, err)
+
+	_, ok := underlyingTransport(cli.client.Transport)
+	assert.Check(t, ok, "underlyingTransport should see through the retry middleware")
+
+	assert.NilError(t, cli.Close())
+	assert.Check(t, cli.Dialer() != nil)
+}
@@ -0,0 +1,32 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/docker/docker/api/types"
+)
+
+// Ping pings the server and returns the value of the "API-Version",
+// "Docker-Experimental" and "OSType" headers.
+func (cli *Client) Ping(ctx context.Context) (types.Ping, error) {
+	var ping types.Ping
+
+	req, err := http.NewRequest(http.MethodGet, cli.getAPIPath(ctx, "/_ping", nil), nil)
+	if err != nil {
+		return ping, err
+	}
+	req = req.WithContext(ctx)
+	resp, err := cli.client.Do(req)
+	if err != nil {
+		return ping, err
+	}
+	defer resp.Body.Close()
+
+	ping.APIVersion = resp.Header.Get("API-Version")
+	ping.OSType = resp.Header.Get("OSType")
+	if resp.Header.Get("Docker-Experimental") == "true" {
+		ping.Experimental = true
+	}
+	return ping, checkResponseErr(resp)
+}
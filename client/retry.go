@@ -0,0 +1,122 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures the backoff used by RetryMiddleware.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retry attempts after the initial
+	// request. A value of 0 disables retrying.
+	MaxRetries int
+	// BaseDelay is the starting delay used for exponential backoff. It
+	// defaults to 100ms if left zero.
+	BaseDelay time.Duration
+	// MaxDelay caps the computed backoff delay. It defaults to 5s if left
+	// zero.
+	MaxDelay time.Duration
+}
+
+// retryableMethods are the HTTP methods the docker API documents as
+// idempotent, and therefore safe to retry without risking the request being
+// applied twice.
+var retryableMethods = map[string]bool{
+	http.MethodGet:    true,
+	http.MethodHead:   true,
+	http.MethodPut:    true,
+	http.MethodDelete: true,
+}
+
+// RetryMiddleware returns request middleware (for use with
+// WithRequestMiddleware) that retries idempotent requests on connection
+// errors and 5xx responses, using exponential backoff with jitter. It
+// honors context cancellation and Retry-After response headers, and never
+// retries a request whose body cannot be replayed (req.GetBody == nil).
+func RetryMiddleware(policy RetryPolicy) func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return &retryRoundTripper{next: next, policy: policy}
+	}
+}
+
+type retryRoundTripper struct {
+	next   http.RoundTripper
+	policy RetryPolicy
+}
+
+// Unwrap returns the http.RoundTripper this middleware wraps, so that
+// Client.Close and Client.Dialer can still reach the underlying
+// *http.Transport through a WithRequestMiddleware chain.
+func (rt *retryRoundTripper) Unwrap() http.RoundTripper {
+	return rt.next
+}
+
+func (rt *retryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !retryableMethods[req.Method] || (req.Body != nil && req.GetBody == nil) {
+		return rt.next.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := rt.next.RoundTrip(req)
+		if err == nil && resp.StatusCode < http.StatusInternalServerError {
+			return resp, nil
+		}
+		if attempt >= rt.policy.MaxRetries {
+			return resp, err
+		}
+
+		delay := rt.policy.backoff(attempt)
+		if err == nil {
+			if ra := retryAfter(resp.Header.Get("Retry-After")); ra > 0 {
+				delay = ra
+			}
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	base := p.BaseDelay
+	if base <= 0 {
+		base = 100 * time.Millisecond
+	}
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = 5 * time.Second
+	}
+
+	d := base << uint(attempt)
+	if d <= 0 || d > maxDelay {
+		d = maxDelay
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+func retryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
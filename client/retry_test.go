@@ -0,0 +1,102 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestRetryMiddlewareRetriesUntilSuccess(t *testing.T) {
+	var calls int
+	rt := RetryMiddleware(RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls < 3 {
+			return &http.Response{StatusCode: http.StatusInternalServerError, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(""))}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("OK"))}, nil
+	}))
+
+	req, err := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	assert.NilError(t, err)
+
+	resp, err := rt.RoundTrip(req)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(resp.StatusCode, http.StatusOK))
+	assert.Check(t, is.Equal(calls, 3))
+}
+
+func TestRetryMiddlewareGivesUpAfterMaxRetries(t *testing.T) {
+	var calls int
+	rt := RetryMiddleware(RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}))
+
+	req, err := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	assert.NilError(t, err)
+
+	resp, err := rt.RoundTrip(req)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(resp.StatusCode, http.StatusServiceUnavailable))
+	assert.Check(t, is.Equal(calls, 3))
+}
+
+func TestRetryMiddlewareSkipsNonIdempotentMethods(t *testing.T) {
+	var calls int
+	rt := RetryMiddleware(RetryPolicy{MaxRetries: 3})(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: http.StatusInternalServerError, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}))
+
+	req, err := http.NewRequest(http.MethodPost, "http://example.com", strings.NewReader("body"))
+	assert.NilError(t, err)
+
+	resp, err := rt.RoundTrip(req)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(resp.StatusCode, http.StatusInternalServerError))
+	assert.Check(t, is.Equal(calls, 1))
+}
+
+func TestRetryMiddlewareSkipsNonSeekableBody(t *testing.T) {
+	var calls int
+	rt := RetryMiddleware(RetryPolicy{MaxRetries: 3})(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: http.StatusInternalServerError, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}))
+
+	pr, pw := io.Pipe()
+	go func() {
+		_, _ = pw.Write([]byte("body"))
+		pw.Close()
+	}()
+
+	req, err := http.NewRequest(http.MethodPut, "http://example.com", pr)
+	assert.NilError(t, err)
+	req.GetBody = nil
+
+	resp, err := rt.RoundTrip(req)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(resp.StatusCode, http.StatusInternalServerError))
+	assert.Check(t, is.Equal(calls, 1))
+}
+
+func TestRetryMiddlewareHonorsContextCancellation(t *testing.T) {
+	rt := RetryMiddleware(RetryPolicy{MaxRetries: 5, BaseDelay: time.Second, MaxDelay: time.Second})(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusInternalServerError, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	assert.NilError(t, err)
+	req = req.WithContext(ctx)
+	cancel()
+
+	_, err = rt.RoundTrip(req)
+	assert.Check(t, is.ErrorIs(err, context.Canceled))
+}
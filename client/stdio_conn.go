@@ -0,0 +1,98 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// stdioConn adapts a child process' stdin/stdout pipes to a net.Conn, so
+// that a subprocess (such as `ssh ... docker system dial-stdio`) can stand
+// in for a real network connection. stderr is captured so that, once the
+// process has exited, a subsequent Read's error can be annotated with
+// whatever it printed (e.g. an ssh auth or host-key failure) instead of a
+// bare io.EOF.
+//
+// in and out must be the caller's own ends of pipes created with os.Pipe,
+// not the result of cmd.StdinPipe/cmd.StdoutPipe: a Cmd only tracks and
+// closes pipe fds it created itself, so owning them here keeps cmd.Wait
+// (run in the background by dialSSH, concurrently with Read) from ever
+// touching the fd a Read might be blocked on. See dialSSH.
+//
+// waitErr and the data stderr points at must only be read after done has
+// been observed closed; they're written by the goroutine dialSSH starts,
+// which closes done only once both are final.
+type stdioConn struct {
+	cmd    *exec.Cmd
+	in     io.WriteCloser
+	out    io.ReadCloser
+	stderr *bytes.Buffer
+
+	done    chan struct{}
+	waitErr error
+}
+
+func (c *stdioConn) Read(b []byte) (int, error) {
+	n, err := c.out.Read(b)
+	if err != nil {
+		err = c.annotate(err)
+	}
+	return n, err
+}
+
+func (c *stdioConn) Write(b []byte) (int, error) { return c.in.Write(b) }
+
+// annotate folds ssh's stderr output into err, but only once the process
+// has actually exited, and only when that exit wasn't simply the clean
+// close that produces an ordinary io.EOF — ssh routinely prints benign
+// warnings (e.g. "Permanently added '<host>' to the list of known hosts")
+// that have nothing to do with a normal end of stream, and turning those
+// into a non-io.EOF error would break callers (io.Copy, bufio.Scanner, ...)
+// that rely on comparing the read error to io.EOF exactly.
+//
+// It never blocks waiting for the process: stdioConn also backs duplex
+// exec/attach streams, where the remote side may be waiting on a write
+// from us before it exits, so blocking Read on cmd.Wait() here could
+// deadlock against a Close call the caller hasn't made yet.
+func (c *stdioConn) annotate(err error) error {
+	select {
+	case <-c.done:
+	default:
+		return err
+	}
+	if err == io.EOF && c.waitErr == nil {
+		return err
+	}
+	msg := strings.TrimSpace(c.stderr.String())
+	if msg == "" {
+		return err
+	}
+	return fmt.Errorf("%w: ssh: %s", err, msg)
+}
+
+func (c *stdioConn) Close() error {
+	inErr := c.in.Close()
+	outErr := c.out.Close()
+	<-c.done
+	if inErr != nil {
+		return inErr
+	}
+	return outErr
+}
+
+func (c *stdioConn) LocalAddr() net.Addr                { return stdioAddr{} }
+func (c *stdioConn) RemoteAddr() net.Addr               { return stdioAddr{} }
+func (c *stdioConn) SetDeadline(t time.Time) error      { return nil }
+func (c *stdioConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *stdioConn) SetWriteDeadline(t time.Time) error { return nil }
+
+// stdioAddr is a placeholder net.Addr for connections backed by a
+// subprocess' stdio pipes rather than a real socket.
+type stdioAddr struct{}
+
+func (stdioAddr) Network() string { return "stdio" }
+func (stdioAddr) String() string  { return "stdio" }
@@ -0,0 +1,19 @@
+package client // import "github.com/docker/docker/client"
+
+import "net/http"
+
+// transportFunc allows us to inject a mock transport for testing. We define it
+// here so it can be used in different _test files.
+type transportFunc func(*http.Request) (*http.Response, error)
+
+func (tf transportFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return tf(req)
+}
+
+// newMockClient creates a new *http.Client with the RoundTripper replaced
+// with a mock one backed by the provided doer func.
+func newMockClient(doer func(*http.Request) (*http.Response, error)) *http.Client {
+	return &http.Client{
+		Transport: transportFunc(doer),
+	}
+}
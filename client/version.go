@@ -0,0 +1,64 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+
+	"github.com/docker/docker/api"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/versions"
+)
+
+// NegotiateAPIVersion queries the API and updates the version to match the
+// API version. NegotiateAPIVersion downgrades the client's requested API
+// version to match the APIVersion if the ping version is lower than the
+// client's current version. If a manual override is in place, either through the
+// DOCKER_API_VERSION environment variable, or if the client is initialized
+// with a fixed version (`WithVersion(xx)`), no negotiation is performed.
+func (cli *Client) NegotiateAPIVersion(ctx context.Context) {
+	ping, _ := cli.Ping(ctx)
+	cli.NegotiateAPIVersionPing(ping)
+}
+
+// NegotiateAPIVersionPing updates the client's version to match the Ping.APIVersion
+// if the ping version is less than the client's current version. NegotiateAPIVersionPing
+// downgrades the client's requested API version to match the APIVersion if the
+// ping version is lower than the client's current version (api.DefaultVersion
+// if none is set). If a manual override is in
+// place, either through the DOCKER_API_VERSION environment variable, or if
+// the client is initialized with a fixed version (`WithVersion(xx)`), no
+// negotiation is performed.
+func (cli *Client) NegotiateAPIVersionPing(p types.Ping) {
+	if cli.manualOverride {
+		return
+	}
+
+	// try the latest version before versioning headers existed
+	if p.APIVersion == "" {
+		p.APIVersion = "1.24"
+	}
+
+	// if the client is not initialized with a version, start with the
+	// latest supported version
+	if cli.version == "" {
+		cli.version = api.DefaultVersion
+	}
+
+	// if server version is lower than the client version, downgrade
+	if versions.LessThan(p.APIVersion, cli.version) {
+		cli.version = p.APIVersion
+	}
+
+	// Store the results, so that automatic API version negotiation (if
+	// enabled) won't be performed on subsequent requests.
+	cli.negotiated = true
+}
+
+// negotiateAPIVersionAutomatic triggers API version negotiation when the
+// client has been configured with WithAPIVersionNegotiation and negotiation
+// has not already taken place, so that it transparently happens on the
+// first request made with the client.
+func (cli *Client) negotiateAPIVersionAutomatic(ctx context.Context) {
+	if cli.negotiateVersion && !cli.negotiated {
+		cli.NegotiateAPIVersion(ctx)
+	}
+}